@@ -0,0 +1,320 @@
+package generichandler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MIME types understood by the built-in codecs.
+const (
+	MIMEApplicationJSON     = "application/json"
+	MIMEApplicationForm     = "application/x-www-form-urlencoded"
+	MIMEApplicationXML      = "application/xml"
+	MIMEApplicationProtobuf = "application/protobuf"
+)
+
+// CodecRegistry maps MIME types to HTTPDecoder/HTTPEncoder pairs for a given RequestType and
+// ResponseType, so a single endpoint can accept JSON, form, XML, or protobuf bodies and respond
+// with JSON, XML, or protobuf without writing a per-format wrapper around it. NewCodecRegistry
+// pre-populates the built-in codecs;
+// RegisterDecoder/RegisterEncoder can add or override entries.
+type CodecRegistry[RequestType, ResponseType any] struct {
+	decoders     map[string]HTTPDecoder[RequestType]
+	encoders     map[string]HTTPEncoder[ResponseType]
+	encoderOrder []string // preserves registration order for wildcard Accept matching
+}
+
+// NewCodecRegistry returns a CodecRegistry with JSON, XML, and protobuf decoders and encoders
+// registered, plus a form decoder. There is no built-in form encoder, so a request that only
+// accepts application/x-www-form-urlencoded is answered 406. The protobuf codec only works if
+// *RequestType/*ResponseType implements proto.Message; it returns an error at decode/encode
+// time otherwise.
+func NewCodecRegistry[RequestType, ResponseType any]() *CodecRegistry[RequestType, ResponseType] {
+	registry := &CodecRegistry[RequestType, ResponseType]{
+		decoders: make(map[string]HTTPDecoder[RequestType]),
+		encoders: make(map[string]HTTPEncoder[ResponseType]),
+	}
+
+	registry.RegisterDecoder(MIMEApplicationJSON, DefaultHTTPDecoder[RequestType])
+	registry.RegisterEncoder(MIMEApplicationJSON, DefaultHTTPEncoder[ResponseType])
+	registry.RegisterDecoder(MIMEApplicationForm, FormHTTPDecoder[RequestType])
+	registry.RegisterDecoder(MIMEApplicationXML, XMLHTTPDecoder[RequestType])
+	registry.RegisterEncoder(MIMEApplicationXML, XMLHTTPEncoder[ResponseType])
+	registry.RegisterDecoder(MIMEApplicationProtobuf, ProtobufHTTPDecoder[RequestType])
+	registry.RegisterEncoder(MIMEApplicationProtobuf, ProtobufHTTPEncoder[ResponseType])
+
+	return registry
+}
+
+// RegisterDecoder associates decoder with mimeType, overwriting any existing entry.
+func (registry *CodecRegistry[RequestType, ResponseType]) RegisterDecoder(mimeType string, decoder HTTPDecoder[RequestType]) {
+	registry.decoders[mimeType] = decoder
+}
+
+// RegisterEncoder associates encoder with mimeType, overwriting any existing entry.
+func (registry *CodecRegistry[RequestType, ResponseType]) RegisterEncoder(mimeType string, encoder HTTPEncoder[ResponseType]) {
+	if _, exists := registry.encoders[mimeType]; !exists {
+		registry.encoderOrder = append(registry.encoderOrder, mimeType)
+	}
+	registry.encoders[mimeType] = encoder
+}
+
+// decoderFor resolves the decoder for a request's Content-Type, defaulting to JSON when no
+// Content-Type is set. It reports ok=false if no decoder is registered for the media type.
+func (registry *CodecRegistry[RequestType, ResponseType]) decoderFor(r *http.Request) (HTTPDecoder[RequestType], string, bool) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = MIMEApplicationJSON
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	decoder, ok := registry.decoders[mediaType]
+	return decoder, mediaType, ok
+}
+
+// encoderFor negotiates the response representation from an Accept header (with q-value
+// support), defaulting to JSON when the header is absent or "*/*". It reports ok=false if
+// none of the requested media types have a registered encoder.
+func (registry *CodecRegistry[RequestType, ResponseType]) encoderFor(r *http.Request) (HTTPEncoder[ResponseType], string, bool) {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		encoder, ok := registry.encoders[MIMEApplicationJSON]
+		return encoder, MIMEApplicationJSON, ok
+	}
+
+	for _, mediaType := range parseAccept(accept) {
+		if mediaType == "*/*" {
+			if encoder, ok := registry.encoders[MIMEApplicationJSON]; ok {
+				return encoder, MIMEApplicationJSON, true
+			}
+			if len(registry.encoderOrder) > 0 {
+				first := registry.encoderOrder[0]
+				return registry.encoders[first], first, true
+			}
+			continue
+		}
+
+		if strings.HasSuffix(mediaType, "/*") {
+			prefix := strings.TrimSuffix(mediaType, "*")
+			for _, registered := range registry.encoderOrder {
+				if strings.HasPrefix(registered, prefix) {
+					return registry.encoders[registered], registered, true
+				}
+			}
+			continue
+		}
+
+		if encoder, ok := registry.encoders[mediaType]; ok {
+			return encoder, mediaType, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// acceptEntry is one comma-separated range from an Accept header, e.g. "application/xml;q=0.9".
+type acceptEntry struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept parses an Accept header into media types ordered from most to least preferred.
+func parseAccept(header string) []string {
+	parts := strings.Split(header, ",")
+	entries := make([]acceptEntry, 0, len(parts))
+
+	for _, part := range parts {
+		fields := strings.Split(part, ";")
+		mediaType := strings.TrimSpace(fields[0])
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = parsed
+			}
+		}
+
+		entries = append(entries, acceptEntry{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].quality > entries[j].quality
+	})
+
+	mediaTypes := make([]string, len(entries))
+	for i, entry := range entries {
+		mediaTypes[i] = entry.mediaType
+	}
+	return mediaTypes
+}
+
+// ToNegotiatedHandlerFunc is like ToHandlerFunc, but instead of a single fixed decoder and
+// encoder it picks them from registry based on the request's Content-Type and Accept
+// headers. If the Content-Type isn't registered, it responds 415 Unsupported Media Type; if
+// none of the requested Accept media types are registered, it responds 406 Not Acceptable.
+func ToNegotiatedHandlerFunc[RequestType, ResponseType any](
+	registry *CodecRegistry[RequestType, ResponseType],
+	endpoint APIEndpoint[RequestType, ResponseType],
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		encoder, encoderMIME, ok := registry.encoderFor(r)
+		if !ok {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		requestData := new(RequestType)
+		if r.Body != nil {
+			decoder, _, ok := registry.decoderFor(r)
+			if !ok {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+
+			var err error
+			requestData, err = decoder(r)
+			if err != nil {
+				status, body := DefaultErrorMapper(fmt.Errorf("%w: %v", ErrorInvalidRequest, err))
+				writeError(w, status, body)
+				return
+			}
+
+			if requestData != nil {
+				if req, isValidatable := (any(requestData)).(Validatable); isValidatable {
+					if err := req.Validate(ctx); err != nil {
+						status, body := DefaultErrorMapper(err)
+						writeError(w, status, body)
+						return
+					}
+				}
+			}
+		} else {
+			requestData = nil
+		}
+
+		responseData, err := endpoint(ctx, requestData)
+		if err != nil {
+			status, body := DefaultErrorMapper(err)
+			writeError(w, status, body)
+			return
+		}
+
+		w.Header().Set("Content-Type", encoderMIME)
+		if err := encoder(w, responseData); err != nil {
+			status, body := DefaultErrorMapper(err)
+			writeError(w, status, body)
+			return
+		}
+	}
+}
+
+// FormHTTPDecoder decodes application/x-www-form-urlencoded bodies into RequestType, using
+// `form:"..."` struct tags to map form field names onto struct fields.
+func FormHTTPDecoder[RequestType any](r *http.Request) (*RequestType, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorInvalidRequest, err)
+	}
+
+	requestData := new(RequestType)
+	value := reflect.ValueOf(requestData).Elem()
+	t := value.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := t.Field(i).Tag.Lookup("form")
+		if !ok || name == "" {
+			continue
+		}
+		raw, present := values[name]
+		if !present {
+			continue
+		}
+		if err := setFieldValue(value.Field(i), raw); err != nil {
+			return nil, fmt.Errorf("%w: form %q: %v", ErrorInvalidRequest, name, err)
+		}
+	}
+
+	return requestData, nil
+}
+
+// XMLHTTPDecoder unmarshals XML from the request body.
+func XMLHTTPDecoder[RequestType any](r *http.Request) (*RequestType, error) {
+	requestData := new(RequestType)
+	if err := xml.NewDecoder(r.Body).Decode(requestData); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorInvalidRequest, err)
+	}
+	return requestData, nil
+}
+
+// XMLHTTPEncoder writes data as XML to an HTTP response.
+func XMLHTTPEncoder[ResponseType any](w http.ResponseWriter, data *ResponseType) error {
+	if data == nil {
+		return nil
+	}
+	return xml.NewEncoder(w).Encode(data)
+}
+
+// ProtobufHTTPDecoder unmarshals a protobuf-encoded request body into RequestType. It returns
+// an error if *RequestType does not implement proto.Message.
+func ProtobufHTTPDecoder[RequestType any](r *http.Request) (*RequestType, error) {
+	requestData := new(RequestType)
+	message, ok := any(requestData).(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T does not implement proto.Message", ErrorInvalidRequest, requestData)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(body, message); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorInvalidRequest, err)
+	}
+
+	return requestData, nil
+}
+
+// ProtobufHTTPEncoder marshals data as protobuf to an HTTP response. It returns an error if
+// *ResponseType does not implement proto.Message.
+func ProtobufHTTPEncoder[ResponseType any](w http.ResponseWriter, data *ResponseType) error {
+	if data == nil {
+		return nil
+	}
+	message, ok := any(data).(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T does not implement proto.Message", ErrorInvalidRequest, data)
+	}
+
+	body, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
@@ -0,0 +1,86 @@
+package generichandler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Isnor/generichandler"
+)
+
+type widget struct {
+	Name string `json:"name" form:"name" xml:"name"`
+}
+
+func widgetEndpointFunc(_ context.Context, w *widget) (*widget, error) {
+	return w, nil
+}
+
+func TestToNegotiatedHandlerFuncJSON(t *testing.T) {
+	registry := generichandler.NewCodecRegistry[widget, widget]()
+	handler := generichandler.ToNegotiatedHandlerFunc(registry, widgetEndpointFunc)
+
+	request := httptest.NewRequest(http.MethodPost, "/widgets", strings.NewReader(`{"name":"cog"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	response := recorder.Result()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", response.StatusCode)
+	}
+	if got := response.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected json content type, got %q", got)
+	}
+}
+
+func TestToNegotiatedHandlerFuncUnsupportedContentType(t *testing.T) {
+	registry := generichandler.NewCodecRegistry[widget, widget]()
+	handler := generichandler.ToNegotiatedHandlerFunc(registry, widgetEndpointFunc)
+
+	request := httptest.NewRequest(http.MethodPost, "/widgets", strings.NewReader(`name=cog`))
+	request.Header.Set("Content-Type", "application/unknown")
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	if recorder.Result().StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415, got %d", recorder.Result().StatusCode)
+	}
+}
+
+func TestToNegotiatedHandlerFuncNotAcceptable(t *testing.T) {
+	registry := generichandler.NewCodecRegistry[widget, widget]()
+	handler := generichandler.ToNegotiatedHandlerFunc(registry, widgetEndpointFunc)
+
+	request := httptest.NewRequest(http.MethodPost, "/widgets", strings.NewReader(`{"name":"cog"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/unknown")
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	if recorder.Result().StatusCode != http.StatusNotAcceptable {
+		t.Errorf("expected 406, got %d", recorder.Result().StatusCode)
+	}
+}
+
+func TestToNegotiatedHandlerFuncForm(t *testing.T) {
+	registry := generichandler.NewCodecRegistry[widget, widget]()
+	handler := generichandler.ToNegotiatedHandlerFunc(registry, widgetEndpointFunc)
+
+	request := httptest.NewRequest(http.MethodPost, "/widgets", strings.NewReader(`name=cog`))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	recorder := httptest.NewRecorder()
+
+	handler(recorder, request)
+
+	if recorder.Result().StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", recorder.Result().StatusCode)
+	}
+}
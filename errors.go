@@ -0,0 +1,82 @@
+package generichandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Sentinel errors recognized by DefaultErrorMapper. Wrap one of these with errors.Is-compatible
+// helpers (e.g. github.com/pkg/errors.WithMessage, fmt.Errorf("%w: ...", ...)) from an endpoint
+// to get the matching HTTP status without reaching for the response writer directly.
+var (
+	ErrorUnauthorized = errors.New("unauthorized")
+	ErrorForbidden    = errors.New("forbidden")
+	ErrorConflict     = errors.New("conflict")
+	ErrorRateLimited  = errors.New("rate limited")
+	ErrorInternal     = errors.New("internal error")
+)
+
+// HTTPCoder can be implemented by an endpoint's own error types to attach a status code
+// directly, bypassing sentinel matching entirely.
+type HTTPCoder interface {
+	HTTPCode() int
+}
+
+// ErrorMapper inspects an error returned by an APIEndpoint and produces the HTTP status code
+// and response body that should be written for it.
+type ErrorMapper func(error) (int, any)
+
+// ProblemDetails is an RFC 7807 "application/problem+json" body.
+type ProblemDetails struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// DefaultErrorMapper maps an error to an HTTP status and a ProblemDetails body. It checks
+// HTTPCoder first, then matches ErrorInvalidRequest, ErrorNotFound, ErrorUnauthorized,
+// ErrorForbidden, ErrorConflict, and ErrorRateLimited via errors.Is, falling back to 500 for
+// anything else (including ErrorInternal).
+func DefaultErrorMapper(err error) (int, any) {
+	status := statusFor(err)
+	return status, &ProblemDetails{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+}
+
+func statusFor(err error) int {
+	var coder HTTPCoder
+	if errors.As(err, &coder) {
+		return coder.HTTPCode()
+	}
+
+	switch {
+	case errors.Is(err, ErrorInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrorUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrorForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrorConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrorRateLimited):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError writes status and body (as produced by an ErrorMapper) as
+// application/problem+json.
+func writeError(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,80 @@
+package generichandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Isnor/generichandler"
+	"github.com/pkg/errors"
+)
+
+type codedError struct{ code int }
+
+func (e codedError) Error() string { return "coded error" }
+func (e codedError) HTTPCode() int { return e.code }
+
+func TestDefaultErrorMapper(t *testing.T) {
+	type testDef struct {
+		name           string
+		err            error
+		expectedStatus int
+	}
+
+	tests := []testDef{
+		{"invalid request", errors.WithMessage(generichandler.ErrorInvalidRequest, "bad"), http.StatusBadRequest},
+		{"not found", errors.WithMessage(generichandler.ErrorNotFound, "missing"), http.StatusNotFound},
+		{"unauthorized", errors.WithMessage(generichandler.ErrorUnauthorized, "nope"), http.StatusUnauthorized},
+		{"forbidden", errors.WithMessage(generichandler.ErrorForbidden, "nope"), http.StatusForbidden},
+		{"conflict", errors.WithMessage(generichandler.ErrorConflict, "exists"), http.StatusConflict},
+		{"rate limited", errors.WithMessage(generichandler.ErrorRateLimited, "slow down"), http.StatusTooManyRequests},
+		{"internal", generichandler.ErrorInternal, http.StatusInternalServerError},
+		{"unmapped", errors.New("boom"), http.StatusInternalServerError},
+		{"http coder", codedError{code: http.StatusTeapot}, http.StatusTeapot},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, body := generichandler.DefaultErrorMapper(test.err)
+			if status != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, status)
+			}
+
+			problem, ok := body.(*generichandler.ProblemDetails)
+			if !ok {
+				t.Fatalf("expected a *ProblemDetails body, got %T", body)
+			}
+			if problem.Status != test.expectedStatus {
+				t.Errorf("expected problem.Status %d, got %d", test.expectedStatus, problem.Status)
+			}
+		})
+	}
+}
+
+func TestToHandlerFuncWritesProblemJSON(t *testing.T) {
+	convertedHandler := generichandler.DefaultJSONHandlerFunc(func(_ context.Context, p *pet) (*pet, error) {
+		return nil, errors.WithMessage(generichandler.ErrorNotFound, "no such pet")
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/pet", nil)
+	recorder := httptest.NewRecorder()
+	convertedHandler(recorder, request)
+
+	response := recorder.Result()
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", response.StatusCode)
+	}
+	if got := response.Header.Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("expected problem+json content type, got %q", got)
+	}
+
+	var problem generichandler.ProblemDetails
+	if err := json.NewDecoder(response.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed decoding problem details: %v", err)
+	}
+	if problem.Status != http.StatusNotFound {
+		t.Errorf("expected problem.Status 404, got %d", problem.Status)
+	}
+}
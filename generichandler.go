@@ -6,6 +6,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -33,6 +35,11 @@ func DefaultHTTPDecoder[RequestType any](request *http.Request) (*RequestType, e
 	requestData := new(RequestType)
 	if request.Body != nil {
 		if err := json.NewDecoder(request.Body).Decode(requestData); err != nil {
+			// a request with no body at all (e.g. a GET) still has a non-nil, empty Body,
+			// so an immediate EOF means "no body was sent" rather than a decode failure
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
 			return nil, err
 		}
 	} else {
@@ -51,59 +58,87 @@ func DefaultHTTPEncoder[ResponseType any](w http.ResponseWriter, data *ResponseT
 	return nil
 }
 
-// ToHandlerFunc returns an http.HandlerFunc composed of decoder, handler, and encoder that somewhat
-// resembles encoder(handler(decoder(request))). It can be used to create `http.Handler`s for endpoints
-// that require a decoder or encoder that isn't provided by this package.  Most common endpoints that
-// expect JSON on the request and response body can be wrapped by this function.
+// ToHandlerFunc returns an http.HandlerFunc that somewhat resembles encoder(handler(decoder(request))),
+// configured by opts. By default it decodes/encodes JSON and maps errors with DefaultErrorMapper; use
+// WithDecoder/WithEncoder to handle a different wire format, and WithMiddleware/WithTimeout/WithLogger to
+// wrap endpoint with cross-cutting concerns that see the typed *RequestType and *ResponseType rather than
+// the raw http.Handler.
 func ToHandlerFunc[RequestType, ResponseType any](
-	decoder HTTPDecoder[RequestType],
 	endpoint APIEndpoint[RequestType, ResponseType],
-	encoder HTTPEncoder[ResponseType],
+	opts ...Option[RequestType, ResponseType],
 ) http.HandlerFunc {
 
+	config := newHandlerConfig[RequestType, ResponseType]()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	// the first middleware given to WithMiddleware should run outermost, so wrap from the
+	// end of the slice inward
+	wrapped := endpoint
+	for i := len(config.middleware) - 1; i >= 0; i-- {
+		wrapped = config.middleware[i](wrapped)
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+
+		if config.maxBodyBytes > 0 && r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, config.maxBodyBytes)
+		}
+
 		// deserialize the body
 		requestData := new(RequestType)
 		if r.Body != nil {
 			var err error
-			requestData, err = decoder(r)
+			requestData, err = config.decoder(r)
 			if err != nil {
-				w.WriteHeader(http.StatusBadRequest)
-				writeErrorJSON(w, err)
+				// a body that fails to decode is always the caller's fault, regardless of
+				// whether the decoder wrapped ErrorInvalidRequest itself
+				status, body := config.errorMapper(fmt.Errorf("%w: %v", ErrorInvalidRequest, err))
+				writeError(w, status, body)
 				return
 			}
 
-			// if the request type has a Validate method defined
-			if req, isValidatable := (any(requestData)).(Validatable); isValidatable {
-				if err := req.Validate(ctx); err != nil {
-					w.WriteHeader(http.StatusBadRequest)
-					writeErrorJSON(w, err)
-					return
+			// if the request type has a Validate method defined; requestData can still be
+			// nil here if the body was empty, so don't validate a zero value it never had
+			if requestData != nil {
+				if req, isValidatable := (any(requestData)).(Validatable); isValidatable {
+					if err := req.Validate(ctx); err != nil {
+						status, body := config.errorMapper(err)
+						writeError(w, status, body)
+						return
+					}
 				}
 			}
 		} else {
 			requestData = nil
 		}
 
-		responseData, err := endpoint(ctx, requestData)
+		responseData, err := wrapped(ctx, requestData)
 		if err != nil {
-			writeErrorJSON(w, err)
+			status, body := config.errorMapper(err)
+			writeError(w, status, body)
 			return
 		}
 
-		if err := encoder(w, responseData); err != nil {
-			writeErrorJSON(w, err)
+		if err := config.encoder(w, responseData); err != nil {
+			status, body := config.errorMapper(err)
+			writeError(w, status, body)
 			return
 		}
 	}
 }
 
-// DefaultJSONHandlerFunc uses the default decoder and encoder to wrap the `endpoint` returns an http.HandlerFunc
-// that attempts to unmarshal JSON from the request body, use it and the request context as arguments to the provided `endpoint`
-// function, and then write the response of that function as JSON
-func DefaultJSONHandlerFunc[RequestType, ResponseType any](endpoint APIEndpoint[RequestType, ResponseType]) http.HandlerFunc {
-	return ToHandlerFunc(DefaultHTTPDecoder[RequestType], endpoint, DefaultHTTPEncoder[ResponseType])
+// DefaultJSONHandlerFunc wraps endpoint the same way ToHandlerFunc does - JSON body in, JSON body out,
+// errors mapped by DefaultErrorMapper - plus whatever opts are given. It exists mainly so call sites that
+// only ever use JSON don't need to spell out WithDecoder/WithEncoder, which are already ToHandlerFunc's
+// defaults.
+func DefaultJSONHandlerFunc[RequestType, ResponseType any](
+	endpoint APIEndpoint[RequestType, ResponseType],
+	opts ...Option[RequestType, ResponseType],
+) http.HandlerFunc {
+	return ToHandlerFunc(endpoint, opts...)
 }
 
 // Most RequestTypes are going to require some kind of validation, and it would be annoying to need to
@@ -113,9 +148,3 @@ func DefaultJSONHandlerFunc[RequestType, ResponseType any](endpoint APIEndpoint[
 type Validatable interface {
 	Validate(context.Context) error
 }
-
-// shorthand function to reduce code verbosity; writes err.Error to a JSON object on the response
-func writeErrorJSON(w http.ResponseWriter, err error) {
-	w.WriteHeader(http.StatusBadRequest)
-	json.NewEncoder(w).Encode(&ErrorResponse{Error: err.Error()})
-}
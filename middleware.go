@@ -0,0 +1,119 @@
+package generichandler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Middleware wraps an APIEndpoint so cross-cutting concerns (auth, logging, tracing,
+// validation) can see the decoded *RequestType and typed *ResponseType, rather than the raw
+// http.Handler. Middlewares compose like an APIEndpoint itself and are attached via
+// WithMiddleware.
+type Middleware[RequestType, ResponseType any] func(APIEndpoint[RequestType, ResponseType]) APIEndpoint[RequestType, ResponseType]
+
+// TimeoutMiddleware bounds how long endpoint is allowed to run by deriving a context with
+// context.WithTimeout.
+func TimeoutMiddleware[RequestType, ResponseType any](timeout time.Duration) Middleware[RequestType, ResponseType] {
+	return func(next APIEndpoint[RequestType, ResponseType]) APIEndpoint[RequestType, ResponseType] {
+		return func(ctx context.Context, request *RequestType) (*ResponseType, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+// RecoveryMiddleware recovers a panic raised by endpoint and turns it into an
+// ErrorInternal-wrapped error, so the panic is mapped to a 500 response instead of crashing
+// the server.
+func RecoveryMiddleware[RequestType, ResponseType any]() Middleware[RequestType, ResponseType] {
+	return func(next APIEndpoint[RequestType, ResponseType]) APIEndpoint[RequestType, ResponseType] {
+		return func(ctx context.Context, request *RequestType) (response *ResponseType, err error) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					err = fmt.Errorf("%w: panic: %v", ErrorInternal, recovered)
+				}
+			}()
+			return next(ctx, request)
+		}
+	}
+}
+
+// LoggingMiddleware logs each call to endpoint via logger, redacting any RequestType field
+// tagged `sensitive:"true"`.
+func LoggingMiddleware[RequestType, ResponseType any](logger *slog.Logger) Middleware[RequestType, ResponseType] {
+	return func(next APIEndpoint[RequestType, ResponseType]) APIEndpoint[RequestType, ResponseType] {
+		return func(ctx context.Context, request *RequestType) (*ResponseType, error) {
+			start := time.Now()
+			response, err := next(ctx, request)
+			duration := time.Since(start)
+
+			if err != nil {
+				logger.ErrorContext(ctx, "endpoint call failed",
+					"request", redactSensitive(request), "duration", duration, "error", err)
+			} else {
+				logger.InfoContext(ctx, "endpoint call succeeded",
+					"request", redactSensitive(request), "duration", duration)
+			}
+			return response, err
+		}
+	}
+}
+
+var tracer = otel.Tracer("github.com/Isnor/generichandler")
+
+// TracingMiddleware starts an otel span named name around each call to endpoint, recording
+// the returned error, if any, on the span.
+func TracingMiddleware[RequestType, ResponseType any](name string) Middleware[RequestType, ResponseType] {
+	return func(next APIEndpoint[RequestType, ResponseType]) APIEndpoint[RequestType, ResponseType] {
+		return func(ctx context.Context, request *RequestType) (*ResponseType, error) {
+			ctx, span := tracer.Start(ctx, name)
+			defer span.End()
+
+			response, err := next(ctx, request)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return response, err
+		}
+	}
+}
+
+// redactSensitive renders request as a loggable value, replacing any field tagged
+// `sensitive:"true"` with a fixed placeholder. Non-struct requests (or a nil request) are
+// returned unchanged.
+func redactSensitive(request any) any {
+	value := reflect.ValueOf(request)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return request
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return request
+	}
+
+	t := value.Type()
+	fields := make(map[string]any, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if sensitive, _ := strconv.ParseBool(field.Tag.Get("sensitive")); sensitive {
+			fields[field.Name] = "[REDACTED]"
+			continue
+		}
+		fields[field.Name] = value.Field(i).Interface()
+	}
+	return fields
+}
@@ -0,0 +1,93 @@
+package generichandler_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Isnor/generichandler"
+)
+
+func TestWithMiddlewareRunsOutermostFirst(t *testing.T) {
+	var order []string
+	record := func(name string) generichandler.Middleware[pet, pet] {
+		return func(next generichandler.APIEndpoint[pet, pet]) generichandler.APIEndpoint[pet, pet] {
+			return func(ctx context.Context, p *pet) (*pet, error) {
+				order = append(order, name+":before")
+				response, err := next(ctx, p)
+				order = append(order, name+":after")
+				return response, err
+			}
+		}
+	}
+
+	handler := generichandler.ToHandlerFunc(
+		func(_ context.Context, p *pet) (*pet, error) { return p, nil },
+		generichandler.WithMiddleware(record("outer"), record("inner")),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/pet", bytes.NewBufferString(`{"Name":"fido","Owner":"jeff","Age":3}`))
+	handler(httptest.NewRecorder(), request)
+
+	expected := []string{"outer:before", "inner:before", "inner:after", "outer:after"}
+	if len(order) != len(expected) {
+		t.Fatalf("expected order %v, got %v", expected, order)
+	}
+	for i, name := range expected {
+		if order[i] != name {
+			t.Errorf("expected order %v, got %v", expected, order)
+			break
+		}
+	}
+}
+
+func TestWithTimeoutCancelsContext(t *testing.T) {
+	handler := generichandler.ToHandlerFunc(
+		func(ctx context.Context, p *pet) (*pet, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		generichandler.WithTimeout[pet, pet](time.Millisecond),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/pet", bytes.NewBufferString(`{"Name":"fido","Owner":"jeff","Age":3}`))
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Result().StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected a timed-out endpoint to map to 500, got %d", recorder.Result().StatusCode)
+	}
+}
+
+func TestRecoveryMiddlewareCatchesPanics(t *testing.T) {
+	handler := generichandler.ToHandlerFunc(
+		func(_ context.Context, p *pet) (*pet, error) { panic("boom") },
+		generichandler.WithMiddleware(generichandler.RecoveryMiddleware[pet, pet]()),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/pet", bytes.NewBufferString(`{"Name":"fido","Owner":"jeff","Age":3}`))
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Result().StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected a recovered panic to map to 500, got %d", recorder.Result().StatusCode)
+	}
+}
+
+func TestWithMaxBodyBytesRejectsLargeBodies(t *testing.T) {
+	handler := generichandler.ToHandlerFunc(
+		func(_ context.Context, p *pet) (*pet, error) { return p, nil },
+		generichandler.WithMaxBodyBytes[pet, pet](4),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/pet", bytes.NewBufferString(`{"Name":"fido","Owner":"jeff","Age":3}`))
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Result().StatusCode == http.StatusOK {
+		t.Error("expected an oversized body to be rejected")
+	}
+}
@@ -0,0 +1,298 @@
+package generichandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Router collects endpoints registered with Handle so that Spec can describe them in a
+// generated OpenAPI document, in addition to actually serving them.
+type Router struct {
+	mux    *http.ServeMux
+	routes []routeInfo
+}
+
+// NewRouter returns an empty Router backed by a fresh http.ServeMux.
+func NewRouter() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+// ServeHTTP lets a Router be used directly as an http.Handler.
+func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router.mux.ServeHTTP(w, r)
+}
+
+type routeInfo struct {
+	method       string
+	path         string
+	summary      string
+	tags         []string
+	requestType  reflect.Type
+	responseType reflect.Type
+}
+
+// RouteOption further describes a route registered with Handle, for use in the OpenAPI
+// document that Spec generates.
+type RouteOption func(*routeInfo)
+
+// WithSummary sets the route's OpenAPI summary.
+func WithSummary(summary string) RouteOption {
+	return func(r *routeInfo) { r.summary = summary }
+}
+
+// WithTags adds to the route's OpenAPI tags.
+func WithTags(tags ...string) RouteOption {
+	return func(r *routeInfo) { r.tags = append(r.tags, tags...) }
+}
+
+// Handle registers endpoint at method and path on router, wiring it up with ToHandlerFunc and
+// handlerOpts the same way a direct http.Handle call would, while also recording the route's
+// Req/Resp types (and routeOpts) so Spec can describe it later. Because RequestType and
+// ResponseType are known at compile time, no separate annotations are needed to document a
+// route beyond routeOpts like WithSummary/WithTags.
+func Handle[RequestType, ResponseType any](
+	router *Router,
+	method, path string,
+	endpoint APIEndpoint[RequestType, ResponseType],
+	routeOpts []RouteOption,
+	handlerOpts ...Option[RequestType, ResponseType],
+) {
+	info := routeInfo{
+		method:       method,
+		path:         path,
+		requestType:  reflect.TypeOf(*new(RequestType)),
+		responseType: reflect.TypeOf(*new(ResponseType)),
+	}
+	for _, opt := range routeOpts {
+		opt(&info)
+	}
+	router.routes = append(router.routes, info)
+	router.mux.HandleFunc(method+" "+path, ToHandlerFunc(endpoint, handlerOpts...))
+}
+
+// Spec generates an OpenAPI 3.1 document describing a Router's registered routes.
+type Spec struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// Handler returns an http.HandlerFunc (suitable for mounting at /openapi.json) that serves
+// the OpenAPI 3.1 document describing router's routes.
+func (s Spec) Handler(router *Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.document(router))
+	}
+}
+
+func (s Spec) document(router *Router) map[string]any {
+	paths := map[string]any{}
+	for _, route := range router.routes {
+		operation := map[string]any{
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": schemaFor(route.responseType)},
+					},
+				},
+				"default": map[string]any{
+					"description": "Error",
+					"content": map[string]any{
+						"application/problem+json": map[string]any{"schema": problemDetailsSchema()},
+					},
+				},
+			},
+		}
+		if route.summary != "" {
+			operation["summary"] = route.summary
+		}
+		if len(route.tags) > 0 {
+			operation["tags"] = route.tags
+		}
+		if parameters := parametersFor(route.requestType); len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+		if body := requestBodySchema(route.requestType); body != nil {
+			operation["requestBody"] = map[string]any{
+				"content": map[string]any{"application/json": map[string]any{"schema": body}},
+			}
+		}
+
+		path, ok := paths[route.path].(map[string]any)
+		if !ok {
+			path = map[string]any{}
+			paths[route.path] = path
+		}
+		path[strings.ToLower(route.method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       s.Title,
+			"version":     s.Version,
+			"description": s.Description,
+		},
+		"paths": paths,
+	}
+}
+
+// SwaggerUIHandler returns an http.HandlerFunc serving a minimal Swagger UI page that loads
+// its spec from specPath (the path Spec.Handler is mounted at).
+func SwaggerUIHandler(specPath string) http.HandlerFunc {
+	page := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><title>API docs</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist/swagger-ui-bundle.js"></script>
+<script>
+  window.onload = () => SwaggerUIBundle({url: %q, dom_id: "#swagger-ui"})
+</script>
+</body>
+</html>`, specPath)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}
+}
+
+// problemDetailsSchema describes the ProblemDetails shape that DefaultErrorMapper responds
+// with for documented error sentinels.
+func problemDetailsSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"type":     map[string]any{"type": "string"},
+			"title":    map[string]any{"type": "string"},
+			"status":   map[string]any{"type": "integer"},
+			"detail":   map[string]any{"type": "string"},
+			"instance": map[string]any{"type": "string"},
+		},
+	}
+}
+
+// parametersFor returns the OpenAPI "parameters" entries for t's path/query/header tagged
+// fields.
+func parametersFor(t reflect.Type) []map[string]any {
+	t = underlyingStruct(t)
+	if t == nil {
+		return nil
+	}
+
+	var parameters []map[string]any
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		for _, source := range [...]string{"path", "query", "header"} {
+			name, ok := field.Tag.Lookup(source)
+			if !ok || name == "" {
+				continue
+			}
+			parameters = append(parameters, map[string]any{
+				"name":     name,
+				"in":       source,
+				"required": source == "path",
+				"schema":   schemaFor(field.Type),
+			})
+		}
+	}
+	return parameters
+}
+
+// requestBodySchema returns the OpenAPI schema for t's JSON body, i.e. everything that isn't
+// sourced from a path/query/header tag. It returns nil if t has no JSON-bound fields.
+func requestBodySchema(t reflect.Type) map[string]any {
+	t = underlyingStruct(t)
+	if t == nil {
+		return nil
+	}
+
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("path"); ok {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("query"); ok {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("header"); ok {
+			continue
+		}
+
+		name := field.Name
+		if jsonTag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(jsonTag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		properties[name] = schemaFor(field.Type)
+	}
+
+	if len(properties) == 0 {
+		return nil
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+// schemaFor returns a minimal OpenAPI schema for t, recursing into structs and slices.
+func schemaFor(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Struct:
+		body := requestBodySchema(t)
+		if body == nil {
+			return map[string]any{"type": "object"}
+		}
+		return body
+	default:
+		return map[string]any{}
+	}
+}
+
+// underlyingStruct unwraps pointers and returns t if it is a struct, or nil otherwise (e.g.
+// for a RequestType of a scalar type, or one left as `any` with no static shape).
+func underlyingStruct(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,64 @@
+package generichandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Isnor/generichandler"
+)
+
+type getPetRequest struct {
+	ID string `path:"id"`
+}
+
+func TestSpecHandlerDescribesRegisteredRoutes(t *testing.T) {
+	router := generichandler.NewRouter()
+	generichandler.Handle(router, http.MethodGet, "/pets/{id}",
+		func(_ context.Context, req *getPetRequest) (*pet, error) {
+			return &pet{Name: req.ID}, nil
+		},
+		[]generichandler.RouteOption{
+			generichandler.WithSummary("Get a pet by ID"),
+			generichandler.WithTags("pets"),
+		},
+	)
+
+	handler := generichandler.Spec{Title: "Pet API", Version: "1.0.0"}.Handler(router)
+
+	request := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	var document map[string]any
+	if err := json.NewDecoder(recorder.Result().Body).Decode(&document); err != nil {
+		t.Fatalf("failed decoding generated spec: %v", err)
+	}
+
+	if document["openapi"] != "3.1.0" {
+		t.Errorf("expected openapi 3.1.0, got %v", document["openapi"])
+	}
+
+	paths, ok := document["paths"].(map[string]any)
+	if !ok {
+		t.Fatal("expected a paths object")
+	}
+	path, ok := paths["/pets/{id}"].(map[string]any)
+	if !ok {
+		t.Fatal("expected /pets/{id} to be documented")
+	}
+	get, ok := path["get"].(map[string]any)
+	if !ok {
+		t.Fatal("expected a get operation")
+	}
+	if get["summary"] != "Get a pet by ID" {
+		t.Errorf("expected summary to be recorded, got %v", get["summary"])
+	}
+
+	parameters, ok := get["parameters"].([]any)
+	if !ok || len(parameters) != 1 {
+		t.Fatalf("expected one path parameter, got %v", get["parameters"])
+	}
+}
@@ -0,0 +1,70 @@
+package generichandler
+
+import (
+	"log/slog"
+	"time"
+)
+
+// handlerConfig holds the resolved settings for a single ToHandlerFunc call, built up by
+// applying Options on top of sane JSON defaults.
+type handlerConfig[RequestType, ResponseType any] struct {
+	decoder      HTTPDecoder[RequestType]
+	encoder      HTTPEncoder[ResponseType]
+	errorMapper  ErrorMapper
+	middleware   []Middleware[RequestType, ResponseType]
+	maxBodyBytes int64
+}
+
+func newHandlerConfig[RequestType, ResponseType any]() *handlerConfig[RequestType, ResponseType] {
+	return &handlerConfig[RequestType, ResponseType]{
+		decoder:     DefaultHTTPDecoder[RequestType],
+		encoder:     DefaultHTTPEncoder[ResponseType],
+		errorMapper: DefaultErrorMapper,
+	}
+}
+
+// Option configures a handler built by ToHandlerFunc/DefaultJSONHandlerFunc. Options that set
+// a single field (WithDecoder, WithEncoder, WithErrorMapper, WithMaxBodyBytes) overwrite
+// whatever was set before them; WithMiddleware, WithTimeout, and WithLogger accumulate onto
+// the middleware chain instead.
+type Option[RequestType, ResponseType any] func(*handlerConfig[RequestType, ResponseType])
+
+// WithDecoder overrides the default JSON request body decoder.
+func WithDecoder[RequestType, ResponseType any](decoder HTTPDecoder[RequestType]) Option[RequestType, ResponseType] {
+	return func(c *handlerConfig[RequestType, ResponseType]) { c.decoder = decoder }
+}
+
+// WithEncoder overrides the default JSON response encoder.
+func WithEncoder[RequestType, ResponseType any](encoder HTTPEncoder[ResponseType]) Option[RequestType, ResponseType] {
+	return func(c *handlerConfig[RequestType, ResponseType]) { c.encoder = encoder }
+}
+
+// WithMiddleware appends middleware to the chain wrapping the endpoint. The first middleware
+// passed here runs outermost, i.e. it sees the call before and after every middleware that
+// follows it.
+func WithMiddleware[RequestType, ResponseType any](middleware ...Middleware[RequestType, ResponseType]) Option[RequestType, ResponseType] {
+	return func(c *handlerConfig[RequestType, ResponseType]) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithErrorMapper overrides DefaultErrorMapper.
+func WithErrorMapper[RequestType, ResponseType any](mapper ErrorMapper) Option[RequestType, ResponseType] {
+	return func(c *handlerConfig[RequestType, ResponseType]) { c.errorMapper = mapper }
+}
+
+// WithTimeout bounds how long the endpoint may run via TimeoutMiddleware, rather than
+// the decoding/encoding around it.
+func WithTimeout[RequestType, ResponseType any](timeout time.Duration) Option[RequestType, ResponseType] {
+	return WithMiddleware(TimeoutMiddleware[RequestType, ResponseType](timeout))
+}
+
+// WithMaxBodyBytes rejects request bodies larger than n, using http.MaxBytesReader.
+func WithMaxBodyBytes[RequestType, ResponseType any](n int64) Option[RequestType, ResponseType] {
+	return func(c *handlerConfig[RequestType, ResponseType]) { c.maxBodyBytes = n }
+}
+
+// WithLogger logs every call to the endpoint via LoggingMiddleware, using logger.
+func WithLogger[RequestType, ResponseType any](logger *slog.Logger) Option[RequestType, ResponseType] {
+	return WithMiddleware(LoggingMiddleware[RequestType, ResponseType](logger))
+}
@@ -0,0 +1,183 @@
+package generichandler
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// PathParamSource extracts a named path parameter from a request. Implementing this as a
+// function type (rather than depending on a specific router) lets callers wire in chi,
+// gorilla/mux, net/http's 1.22+ ServeMux path values, or anything else without this package
+// importing any particular router.
+type PathParamSource func(*http.Request, string) string
+
+// DefaultPathParamSource reads path parameters using the standard library's ServeMux
+// (Go 1.22+), via (*http.Request).PathValue.
+func DefaultPathParamSource(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+// StructTagDecoder provides an HTTPDecoder (its Decode method) that, in addition to unmarshalling the request body as
+// JSON via DefaultHTTPDecoder, populates fields on RequestType tagged with `path:"..."`,
+// `query:"..."`, and `header:"..."` from the incoming *http.Request. This is useful for
+// RequestTypes that need more than a JSON body, e.g. a route parameter like `/pets/{id}` or
+// a paging parameter passed as `?limit=10`.
+type StructTagDecoder[RequestType any] struct {
+	// PathParams resolves named path parameters. Defaults to DefaultPathParamSource.
+	PathParams PathParamSource
+}
+
+// NewStructTagDecoder returns a StructTagDecoder using DefaultPathParamSource.
+func NewStructTagDecoder[RequestType any]() *StructTagDecoder[RequestType] {
+	return &StructTagDecoder[RequestType]{PathParams: DefaultPathParamSource}
+}
+
+// Decode has the HTTPDecoder[RequestType] signature, so d.Decode can be passed to WithDecoder.
+// It first decodes the JSON body (if any) using
+// DefaultHTTPDecoder, then binds path, query, and header tagged fields onto the result.
+func (d *StructTagDecoder[RequestType]) Decode(r *http.Request) (*RequestType, error) {
+	requestData, err := DefaultHTTPDecoder[RequestType](r)
+	if err != nil {
+		return nil, err
+	}
+	if requestData == nil {
+		requestData = new(RequestType)
+	}
+
+	pathParams := d.PathParams
+	if pathParams == nil {
+		pathParams = DefaultPathParamSource
+	}
+
+	if err := bindTaggedFields(requestData, r, pathParams); err != nil {
+		return nil, err
+	}
+
+	return requestData, nil
+}
+
+// taggedField describes a single struct field that should be populated from the request.
+type taggedField struct {
+	index  []int
+	source string // "path", "query", or "header"
+	name   string
+}
+
+// taggedFieldsCache avoids re-reflecting over the same RequestType on every request.
+var taggedFieldsCache sync.Map // map[reflect.Type][]taggedField
+
+// bindTaggedFields populates path/query/header tagged fields of dest from r.
+func bindTaggedFields(dest any, r *http.Request, pathParams PathParamSource) error {
+	value := reflect.ValueOf(dest).Elem()
+	fields := taggedFieldsFor(value.Type())
+	if len(fields) == 0 {
+		return nil
+	}
+
+	query := r.URL.Query()
+
+	for _, field := range fields {
+		var raw []string
+		switch field.source {
+		case "path":
+			if v := pathParams(r, field.name); v != "" {
+				raw = []string{v}
+			}
+		case "query":
+			raw = query[field.name]
+		case "header":
+			if v := r.Header.Get(field.name); v != "" {
+				raw = []string{v}
+			}
+		}
+
+		if len(raw) == 0 {
+			continue
+		}
+
+		fieldValue := value.FieldByIndex(field.index)
+		if err := setFieldValue(fieldValue, raw); err != nil {
+			return fmt.Errorf("%w: %s %q: %v", ErrorInvalidRequest, field.source, field.name, err)
+		}
+	}
+
+	return nil
+}
+
+// taggedFieldsFor returns the path/query/header tagged fields of t, computing and caching
+// them on first use.
+func taggedFieldsFor(t reflect.Type) []taggedField {
+	if cached, ok := taggedFieldsCache.Load(t); ok {
+		return cached.([]taggedField)
+	}
+
+	var fields []taggedField
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		for _, source := range [...]string{"path", "query", "header"} {
+			name, ok := structField.Tag.Lookup(source)
+			if !ok || name == "" {
+				continue
+			}
+			fields = append(fields, taggedField{index: structField.Index, source: source, name: name})
+		}
+	}
+
+	cached, _ := taggedFieldsCache.LoadOrStore(t, fields)
+	return cached.([]taggedField)
+}
+
+// setFieldValue coerces raw string values from a path/query/header into field, supporting
+// common scalar kinds and slices of those kinds for repeated query parameters.
+func setFieldValue(field reflect.Value, raw []string) error {
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(raw), len(raw))
+		for i, v := range raw {
+			if err := setScalarValue(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setScalarValue(field, raw[0])
+}
+
+// setScalarValue coerces a single string into field using strconv.
+func setScalarValue(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
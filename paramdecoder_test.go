@@ -0,0 +1,58 @@
+package generichandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Isnor/generichandler"
+)
+
+// searchRequest exercises path, query, and header binding alongside the usual JSON body.
+type searchRequest struct {
+	ID        string   `path:"id"`
+	Limit     int      `query:"limit"`
+	Tags      []string `query:"tag"`
+	RequestID string   `header:"X-Request-ID"`
+	Body      string   `json:"body"`
+}
+
+func TestStructTagDecoder(t *testing.T) {
+	decoder := generichandler.NewStructTagDecoder[searchRequest]()
+
+	request := httptest.NewRequest(http.MethodGet, "/widgets/abc123?limit=10&tag=a&tag=b", nil)
+	request.SetPathValue("id", "abc123")
+	request.Header.Set("X-Request-ID", "req-1")
+
+	result, err := decoder.Decode(request)
+	if err != nil {
+		t.Fatalf("did not expect an error: %v", err)
+	}
+
+	if result.ID != "abc123" {
+		t.Errorf("expected path param to be bound, got %q", result.ID)
+	}
+	if result.Limit != 10 {
+		t.Errorf("expected query param to be bound, got %d", result.Limit)
+	}
+	if len(result.Tags) != 2 || result.Tags[0] != "a" || result.Tags[1] != "b" {
+		t.Errorf("expected repeated query params to be bound, got %v", result.Tags)
+	}
+	if result.RequestID != "req-1" {
+		t.Errorf("expected header to be bound, got %q", result.RequestID)
+	}
+}
+
+func TestStructTagDecoderInvalidScalar(t *testing.T) {
+	decoder := generichandler.NewStructTagDecoder[searchRequest]()
+
+	request := httptest.NewRequest(http.MethodGet, "/widgets/abc123?limit=not-a-number", nil)
+	request.SetPathValue("id", "abc123")
+
+	if _, err := decoder.Decode(request); err == nil {
+		t.Error("expected an error for a non-numeric limit")
+	} else if !errors.Is(err, generichandler.ErrorInvalidRequest) {
+		t.Errorf("expected the error to wrap ErrorInvalidRequest, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package generichandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// StreamingEndpoint is like APIEndpoint, but instead of returning a single ResponseType it
+// sends zero or more values on responses before returning. This suits long-poll, progress,
+// and live-update endpoints that APIEndpoint's single-response shape can't express.
+type StreamingEndpoint[RequestType, ResponseType any] func(ctx context.Context, request *RequestType, responses chan<- *ResponseType) error
+
+// StreamFramer writes one streamed value - or a terminal error, since headers are already
+// committed by the time a stream is underway - to the response.
+type StreamFramer[ResponseType any] interface {
+	// ContentType is written as the response's Content-Type before the first event.
+	ContentType() string
+	// WriteEvent writes a single streamed value. The caller flushes afterward.
+	WriteEvent(w http.ResponseWriter, data *ResponseType) error
+	// WriteError writes a terminal, in-band error frame. The caller flushes afterward.
+	WriteError(w http.ResponseWriter, err error) error
+}
+
+type lastEventIDKey struct{}
+
+// LastEventID returns the client's Last-Event-ID header value, if any, letting a
+// StreamingEndpoint resume a previously interrupted SSE stream where it left off.
+func LastEventID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(lastEventIDKey{}).(string)
+	return id, ok
+}
+
+// ToStreamingHandlerFunc returns an http.HandlerFunc that decodes the request with decoder,
+// runs endpoint, and writes every value it sends on its responses channel using framer -
+// flushing after each one - until endpoint returns or the client disconnects
+// (r.Context().Done()). A mid-stream error from endpoint is written as a terminal frame via
+// framer.WriteError rather than a status code, since the response headers are already sent.
+func ToStreamingHandlerFunc[RequestType, ResponseType any](
+	decoder HTTPDecoder[RequestType],
+	endpoint StreamingEndpoint[RequestType, ResponseType],
+	framer StreamFramer[ResponseType],
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if id := r.Header.Get("Last-Event-ID"); id != "" {
+			ctx = context.WithValue(ctx, lastEventIDKey{}, id)
+		}
+
+		requestData := new(RequestType)
+		if r.Body != nil {
+			var err error
+			requestData, err = decoder(r)
+			if err != nil {
+				status, body := DefaultErrorMapper(fmt.Errorf("%w: %v", ErrorInvalidRequest, err))
+				writeError(w, status, body)
+				return
+			}
+		} else {
+			requestData = nil
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			status, body := DefaultErrorMapper(fmt.Errorf("%w: response writer does not support streaming", ErrorInternal))
+			writeError(w, status, body)
+			return
+		}
+
+		w.Header().Set("Content-Type", framer.ContentType())
+		w.WriteHeader(http.StatusOK)
+
+		responses := make(chan *ResponseType)
+		endpointErr := make(chan error, 1)
+		go func() {
+			defer close(responses)
+			endpointErr <- endpoint(ctx, requestData, responses)
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case response, open := <-responses:
+				if !open {
+					if err := <-endpointErr; err != nil {
+						framer.WriteError(w, err)
+						flusher.Flush()
+					}
+					return
+				}
+				if err := framer.WriteEvent(w, response); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// ndjsonFramer streams newline-delimited JSON, one value per line.
+type ndjsonFramer[ResponseType any] struct{}
+
+// NDJSONFramer streams responses as application/x-ndjson, one JSON value per line.
+func NDJSONFramer[ResponseType any]() StreamFramer[ResponseType] {
+	return ndjsonFramer[ResponseType]{}
+}
+
+func (ndjsonFramer[ResponseType]) ContentType() string { return "application/x-ndjson" }
+
+func (ndjsonFramer[ResponseType]) WriteEvent(w http.ResponseWriter, data *ResponseType) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+func (ndjsonFramer[ResponseType]) WriteError(w http.ResponseWriter, err error) error {
+	return json.NewEncoder(w).Encode(&ErrorResponse{Error: err.Error()})
+}
+
+// sseFramer streams responses as Server-Sent Events, assigning each event an incrementing id
+// so a client can resume via Last-Event-ID after a dropped connection.
+type sseFramer[ResponseType any] struct {
+	nextID int
+}
+
+// SSEFramer streams responses as text/event-stream, JSON-encoding each value as the event's
+// `data:` field.
+func SSEFramer[ResponseType any]() StreamFramer[ResponseType] {
+	return &sseFramer[ResponseType]{}
+}
+
+func (f *sseFramer[ResponseType]) ContentType() string { return "text/event-stream" }
+
+func (f *sseFramer[ResponseType]) WriteEvent(w http.ResponseWriter, data *ResponseType) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	f.nextID++
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", f.nextID, body)
+	return err
+}
+
+func (f *sseFramer[ResponseType]) WriteError(w http.ResponseWriter, err error) error {
+	body, marshalErr := json.Marshal(&ErrorResponse{Error: err.Error()})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	_, writeErr := fmt.Fprintf(w, "event: error\ndata: %s\n\n", body)
+	return writeErr
+}
@@ -0,0 +1,99 @@
+package generichandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Isnor/generichandler"
+)
+
+func countUpEndpoint(ctx context.Context, p *pet, responses chan<- *pet) error {
+	for age := uint(1); age <= 3; age++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case responses <- &pet{Name: p.Name, Age: age}:
+		}
+	}
+	return nil
+}
+
+func TestToStreamingHandlerFuncNDJSON(t *testing.T) {
+	handler := generichandler.ToStreamingHandlerFunc(
+		generichandler.DefaultHTTPDecoder[pet],
+		countUpEndpoint,
+		generichandler.NDJSONFramer[pet](),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/pet/stream", strings.NewReader(`{"Name":"fido"}`))
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	response := recorder.Result()
+	if got := response.Header.Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("expected ndjson content type, got %q", got)
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var ages []uint
+	for {
+		var p pet
+		if err := decoder.Decode(&p); err != nil {
+			break
+		}
+		ages = append(ages, p.Age)
+	}
+	if len(ages) != 3 || ages[0] != 1 || ages[2] != 3 {
+		t.Errorf("expected three increasing ages, got %v", ages)
+	}
+}
+
+func TestToStreamingHandlerFuncSSE(t *testing.T) {
+	handler := generichandler.ToStreamingHandlerFunc(
+		generichandler.DefaultHTTPDecoder[pet],
+		countUpEndpoint,
+		generichandler.SSEFramer[pet](),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/pet/stream", strings.NewReader(`{"Name":"fido"}`))
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	response := recorder.Result()
+	if got := response.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("expected event-stream content type, got %q", got)
+	}
+
+	body := recorder.Body.String()
+	for _, want := range []string{"id: 1", "id: 2", "id: 3", "data:"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q, got %q", want, body)
+		}
+	}
+}
+
+func TestToStreamingHandlerFuncMidStreamError(t *testing.T) {
+	failingEndpoint := func(ctx context.Context, p *pet, responses chan<- *pet) error {
+		responses <- &pet{Name: p.Name, Age: 1}
+		return generichandler.ErrorInternal
+	}
+
+	handler := generichandler.ToStreamingHandlerFunc(
+		generichandler.DefaultHTTPDecoder[pet],
+		failingEndpoint,
+		generichandler.SSEFramer[pet](),
+	)
+
+	request := httptest.NewRequest(http.MethodPost, "/pet/stream", strings.NewReader(`{"Name":"fido"}`))
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	body := recorder.Body.String()
+	if !strings.Contains(body, "event: error") {
+		t.Errorf("expected a terminal error event, got %q", body)
+	}
+}